@@ -0,0 +1,247 @@
+package trie
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+)
+
+// defaultCacheSize bounds the number of backend-loaded nodes NewTrieWithBackend
+// and OpenTrie keep in memory at once.
+const defaultCacheSize = 1024
+
+// Backend is a content-addressed store a Trie can keep its nodes in instead
+// of the Go heap, so that tries larger than RAM can be queried and grown
+// incrementally.
+type Backend interface {
+	Get(key []byte) ([]byte, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+}
+
+// NewTrieWithBackend returns a new, empty Trie whose committed nodes are
+// stored in b rather than kept entirely in memory.
+func NewTrieWithBackend(b Backend) *Trie {
+	t := NewTrie()
+	t.backend = b
+	t.cache = newLRU(defaultCacheSize)
+	t.dirty = make(map[*node]bool)
+	return t
+}
+
+// OpenTrie reopens a trie previously written to b via Commit, starting from
+// its content-addressed root. Nodes below the root are loaded lazily as they
+// are visited.
+func OpenTrie(root []byte, b Backend) (*Trie, error) {
+	t := NewTrieWithBackend(b)
+	raw, err := b.Get(root)
+	if err != nil {
+		return nil, err
+	}
+	n, err := decodeNode(raw)
+	if err != nil {
+		return nil, err
+	}
+	t.root = n
+	return t, nil
+}
+
+// Err returns the first error encountered while lazily loading nodes from the
+// backend, if any.
+func (t *Trie) Err() error {
+	return t.err
+}
+
+// Commit flushes every dirty node to the backend and returns the
+// content-addressed hash of the root, which can later be passed to OpenTrie.
+// Commit does not evict already-resolved nodes back to backend references;
+// callers that need a strict memory bound should reopen the trie with
+// OpenTrie after committing.
+func (t *Trie) Commit() ([]byte, error) {
+	if t.backend == nil {
+		return nil, errors.New("trie: no backend configured")
+	}
+	root, err := t.commitNode(t.root)
+	if err != nil {
+		return nil, err
+	}
+	t.dirty = make(map[*node]bool)
+	return root, nil
+}
+
+func (t *Trie) commitNode(n *node) ([]byte, error) {
+	enc := encodedNode{IsLeaf: n.isLeaf, Data: n.data}
+	for _, k := range keys(n.edges, true) {
+		e := n.edges[k]
+		ref := e.ref
+		if ref == nil || t.dirty[e.next] {
+			child := t.child(e)
+			var err error
+			ref, err = t.commitNode(child)
+			if err != nil {
+				return nil, err
+			}
+		}
+		enc.Edges = append(enc.Edges, encodedEdge{Label: e.label, Ref: ref})
+	}
+
+	raw, err := encodeNode(enc)
+	if err != nil {
+		return nil, err
+	}
+	ref := contentHash(raw)
+	if err := t.backend.Put(ref, raw); err != nil {
+		return nil, err
+	}
+	t.cache.put(string(ref), n)
+	return ref, nil
+}
+
+// child returns the in-memory node e points to, loading and caching it from
+// the backend first if only e.ref is known.
+func (t *Trie) child(e *edge) *node {
+	if e.next != nil || t.backend == nil {
+		return e.next
+	}
+	if n, ok := t.cache.get(string(e.ref)); ok {
+		return n
+	}
+	raw, err := t.backend.Get(e.ref)
+	if err != nil {
+		t.err = err
+		return nil
+	}
+	n, err := decodeNode(raw)
+	if err != nil {
+		t.err = err
+		return nil
+	}
+	t.cache.put(string(e.ref), n)
+	return n
+}
+
+// markBackendDirty marks every node on the path to key as needing to be
+// reflushed on the next Commit.
+func (t *Trie) markBackendDirty(key string) {
+	cur := t.root
+	t.dirty[cur] = true
+	bPref := []byte(key)
+	for len(bPref) > 0 {
+		e, exists := cur.edges[bPref[0]]
+		if !exists {
+			return
+		}
+		splitIdx := getFirstMismatch(bPref, e.label)
+		if splitIdx > len(bPref) {
+			splitIdx = len(bPref)
+		}
+		cur = t.child(e)
+		t.dirty[cur] = true
+		bPref = bPref[splitIdx:]
+	}
+}
+
+// markBackendDirtyAll marks every in-memory node as needing to be reflushed
+// on the next Commit; used after DeleteItem, whose edge-merging can touch an
+// unbounded set of ancestors.
+func (t *Trie) markBackendDirtyAll() {
+	var walk func(n *node)
+	walk = func(n *node) {
+		t.dirty[n] = true
+		for _, e := range n.edges {
+			if e.next != nil {
+				walk(e.next)
+			}
+		}
+	}
+	walk(t.root)
+}
+
+// encodedNode and encodedEdge are the gob-serializable form of a node stored
+// in a Backend: edges reference children by content hash rather than by
+// pointer.
+type encodedNode struct {
+	IsLeaf bool
+	Data   []byte
+	Edges  []encodedEdge
+}
+
+type encodedEdge struct {
+	Label []byte
+	Ref   []byte
+}
+
+func encodeNode(enc encodedNode) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(enc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeNode(raw []byte) (*node, error) {
+	var enc encodedNode
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&enc); err != nil {
+		return nil, err
+	}
+	n := &node{isLeaf: enc.IsLeaf, data: enc.Data, edges: make(map[byte]*edge)}
+	for _, ee := range enc.Edges {
+		n.edges[ee.Label[0]] = &edge{label: ee.Label, ref: ee.Ref}
+	}
+	return n, nil
+}
+
+// contentHash returns the content address used to key a node in a Backend.
+func contentHash(raw []byte) []byte {
+	sum := sha256.Sum256(raw)
+	return sum[:]
+}
+
+// lru is a bounded, least-recently-used cache of backend-loaded nodes, keyed
+// by their content hash.
+type lru struct {
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	node *node
+}
+
+func newLRU(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *lru) get(key string) (*node, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruEntry).node, true
+}
+
+func (c *lru) put(key string, n *node) {
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*lruEntry).node = n
+		return
+	}
+	el := c.ll.PushFront(&lruEntry{key: key, node: n})
+	c.items[key] = el
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
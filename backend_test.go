@@ -0,0 +1,66 @@
+package trie
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memBackend is an in-memory Backend used to exercise NewTrieWithBackend,
+// Commit and OpenTrie without a real KV store.
+type memBackend struct {
+	store map[string][]byte
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{store: make(map[string][]byte)}
+}
+
+func (b *memBackend) Get(key []byte) ([]byte, error) {
+	v, ok := b.store[string(key)]
+	if !ok {
+		return nil, errors.New("memBackend: not found")
+	}
+	return v, nil
+}
+
+func (b *memBackend) Put(key, value []byte) error {
+	b.store[string(key)] = value
+	return nil
+}
+
+func (b *memBackend) Delete(key []byte) error {
+	delete(b.store, string(key))
+	return nil
+}
+
+func Test_TrieWithBackend(t *testing.T) {
+	backend := newMemBackend()
+	trie := NewTrieWithBackend(backend)
+	for _, word := range []string{"hello", "world", "home", "homework"} {
+		trie.InsertItem(word, []byte(word+"-value"))
+	}
+
+	root, err := trie.Commit()
+	require.NoError(t, err)
+	assert.NotEmpty(t, root)
+
+	reopened, err := OpenTrie(root, backend)
+	require.NoError(t, err)
+
+	assert.True(t, reopened.HasItem("homework"))
+	assert.Equal(t, "homework-value", string(reopened.GetItem("homework")))
+	assert.Equal(t, "home-value", string(reopened.GetItem("home")))
+	assert.NoError(t, reopened.Err())
+
+	reopened.InsertItem("workshop", []byte("workshop-value"))
+	assert.True(t, reopened.HasItem("workshop"))
+}
+
+func Test_Commit_NoBackend(t *testing.T) {
+	trie := NewTrie()
+	_, err := trie.Commit()
+	assert.Error(t, err)
+}
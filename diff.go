@@ -0,0 +1,111 @@
+package trie
+
+import "bytes"
+
+// Merge inserts every key from other into t. If both tries already carry
+// data for the same key, resolve decides the final value; a nil resolve
+// means other's value always wins.
+func (t *Trie) Merge(other *Trie, resolve func(key string, a, b []byte) []byte) {
+	other.Traverse(func(step int, path string, data []byte, isLeaf bool) (bool, error) {
+		if !isLeaf {
+			return true, nil
+		}
+		if resolve != nil && t.HasItem(path) {
+			data = resolve(path, t.GetItem(path), data)
+		}
+		t.InsertItem(path, data)
+		return true, nil
+	})
+}
+
+// Diff walks a and b in lockstep over their sorted edges and reports keys
+// added in b, keys removed from b (i.e. present only in a), and keys present
+// in both with differing data. It exploits the radix structure: whenever the
+// two tries diverge, every key in the subtree on either side is reported as
+// added or removed without comparing it against the other trie. Children are resolved
+// through each Trie's child method, so a and b may be backend-backed tries
+// with edges not yet loaded into memory.
+func Diff(a, b *Trie) (added, removed, changed []string) {
+	diffNodes(a, b, a.root, b.root, "", &added, &removed, &changed)
+	return
+}
+
+func diffNodes(at, bt *Trie, an, bn *node, prefix string, added, removed, changed *[]string) {
+	switch {
+	case an.isLeaf && bn.isLeaf:
+		if !bytes.Equal(an.data, bn.data) {
+			*changed = append(*changed, prefix)
+		}
+	case bn.isLeaf:
+		*added = append(*added, prefix)
+	case an.isLeaf:
+		*removed = append(*removed, prefix)
+	}
+
+	aKeys, bKeys := keys(an.edges, true), keys(bn.edges, true)
+	i, j := 0, 0
+	for i < len(aKeys) || j < len(bKeys) {
+		switch {
+		case j == len(bKeys) || (i < len(aKeys) && aKeys[i] < bKeys[j]):
+			ae := an.edges[aKeys[i]]
+			collectInto(at, ae, prefix+string(ae.label), removed)
+			i++
+		case i == len(aKeys) || (j < len(bKeys) && bKeys[j] < aKeys[i]):
+			be := bn.edges[bKeys[j]]
+			collectInto(bt, be, prefix+string(be.label), added)
+			j++
+		default:
+			diffEdges(at, bt, an.edges[aKeys[i]], bn.edges[bKeys[j]], prefix, added, removed, changed)
+			i++
+			j++
+		}
+	}
+}
+
+// diffEdges compares two edges that share a first byte, splitting on the
+// point their labels stop matching so only the genuinely differing part of
+// the tree is ever visited leaf by leaf.
+func diffEdges(at, bt *Trie, ae, be *edge, prefix string, added, removed, changed *[]string) {
+	minLen := len(ae.label)
+	if len(be.label) < minLen {
+		minLen = len(be.label)
+	}
+
+	common := getFirstMismatch(ae.label, be.label)
+	if common < minLen {
+		// the labels themselves diverge; the two subtrees are unrelated.
+		collectInto(at, ae, prefix+string(ae.label), removed)
+		collectInto(bt, be, prefix+string(be.label), added)
+		return
+	}
+
+	// one label is a prefix of the other (or they're equal); descend with a
+	// virtual node standing in for whichever side still has label left.
+	aNode := tailNode(at.child(ae), ae.label, common)
+	bNode := tailNode(bt.child(be), be.label, common)
+	diffNodes(at, bt, aNode, bNode, prefix+string(ae.label[:common]), added, removed, changed)
+}
+
+// tailNode returns next itself if label is fully consumed by splitAt,
+// otherwise a single-edge node standing in for the remaining label tail.
+func tailNode(next *node, label []byte, splitAt int) *node {
+	if splitAt == len(label) {
+		return next
+	}
+	tail := label[splitAt:]
+	return &node{edges: map[byte]*edge{tail[0]: {label: tail, next: next}}}
+}
+
+// collectInto appends every leaf key under the node e leads to, reached via
+// prefix, to out. e is resolved through t.child so a not-yet-loaded backend
+// edge is fetched rather than dereferenced directly.
+func collectInto(t *Trie, e *edge, prefix string, out *[]string) {
+	n := t.child(e)
+	if n.isLeaf {
+		*out = append(*out, prefix)
+	}
+	for _, k := range keys(n.edges, true) {
+		ce := n.edges[k]
+		collectInto(t, ce, prefix+string(ce.label), out)
+	}
+}
@@ -0,0 +1,124 @@
+package trie
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTrie(items map[string]string) *Trie {
+	t := NewTrie()
+	for k, v := range items {
+		t.InsertItem(k, []byte(v))
+	}
+	return t
+}
+
+func Test_Merge(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        map[string]string
+		b        map[string]string
+		resolve  func(key string, a, b []byte) []byte
+		checkFor func(*Trie) bool
+	}{
+		{
+			name: "other wins when resolve is nil",
+			a:    map[string]string{"home": "a-value"},
+			b:    map[string]string{"home": "b-value", "world": "world-value"},
+			checkFor: func(t *Trie) bool {
+				return string(t.GetItem("home")) == "b-value" && t.HasItem("world")
+			},
+		},
+		{
+			name: "resolve picks the final value on conflict",
+			a:    map[string]string{"home": "a-value"},
+			b:    map[string]string{"home": "b-value"},
+			resolve: func(key string, a, b []byte) []byte {
+				return append(append([]byte{}, a...), b...)
+			},
+			checkFor: func(t *Trie) bool {
+				return string(t.GetItem("home")) == "a-valueb-value"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := buildTrie(tc.a)
+			b := buildTrie(tc.b)
+			a.Merge(b, tc.resolve)
+			assert.True(t, tc.checkFor(a))
+		})
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	testCases := []struct {
+		name        string
+		a           map[string]string
+		b           map[string]string
+		wantAdded   []string
+		wantRemoved []string
+		wantChanged []string
+	}{
+		{
+			name:      "added keys under a diverging subtree",
+			a:         map[string]string{"hello": "1"},
+			b:         map[string]string{"hello": "1", "world": "1"},
+			wantAdded: []string{"world"},
+		},
+		{
+			name:        "removed keys under a diverging subtree",
+			a:           map[string]string{"hello": "1", "world": "1"},
+			b:           map[string]string{"hello": "1"},
+			wantRemoved: []string{"world"},
+		},
+		{
+			name:        "changed data for a key present on both sides",
+			a:           map[string]string{"home": "1", "homework": "1"},
+			b:           map[string]string{"home": "2", "homework": "1"},
+			wantChanged: []string{"home"},
+		},
+		{
+			name:      "split introduced only on one side",
+			a:         map[string]string{"home": "1"},
+			b:         map[string]string{"home": "1", "homework": "1"},
+			wantAdded: []string{"homework"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed, changed := Diff(buildTrie(tc.a), buildTrie(tc.b))
+			sort.Strings(added)
+			sort.Strings(removed)
+			sort.Strings(changed)
+			assert.Equal(t, tc.wantAdded, added)
+			assert.Equal(t, tc.wantRemoved, removed)
+			assert.Equal(t, tc.wantChanged, changed)
+		})
+	}
+}
+
+func Test_Diff_BackendBackedTrie(t *testing.T) {
+	backend := newMemBackend()
+	a := NewTrieWithBackend(backend)
+	for _, word := range []string{"hello", "world", "home", "homework"} {
+		a.InsertItem(word, []byte(word+"-value"))
+	}
+	root, err := a.Commit()
+	require.NoError(t, err)
+
+	reopened, err := OpenTrie(root, backend)
+	require.NoError(t, err)
+
+	b := buildTrie(map[string]string{"hello": "hello-value", "world": "world-value", "home": "home-value", "homework": "homework-value", "workshop": "workshop-value"})
+
+	added, removed, changed := Diff(reopened, b)
+	assert.Equal(t, []string{"workshop"}, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+}
@@ -1,8 +1,10 @@
-// This example shows how to use the trie package to store file paths.
+// This example shows how to use the triefs package to turn an fs.FS into a
+// trie.Trie and back into a read-only fs.FS.
 //
-// The file paths are stored as keys and the file type (file or directory) as data.
+// It builds a trie from a small in-memory filesystem with
+// triefs.BuildFromFS, then walks the trie exposed through triefs.AsFS with
+// fs.WalkDir to print every regular file it contains.
 //
-// The PrintFiles method prints all the file paths in the trie.
 // The output of this example is:
 //   - /etc/nginx/nginx.conf
 //   - /etc/sshd/sshd.conf
@@ -11,59 +13,42 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"testing/fstest"
 
-	trie "github.com/souleb/gotrie"
+	"github.com/souleb/gotrie/triefs"
 )
 
-const (
-	dir  = "dir"
-	file = "file"
-)
-
-// StringTrie is a wrapper around trie.Trie.
-type StringTrie struct {
-	trie.Trie
-	out io.Writer
+// source is the filesystem main builds its example trie from.
+var source = fstest.MapFS{
+	"etc/nginx/nginx.conf":     &fstest.MapFile{Data: []byte("server {}")},
+	"etc/sshd/sshd.conf":       &fstest.MapFile{Data: []byte("Port 22")},
+	"var/log/nginx/access.log": &fstest.MapFile{Data: []byte("- - -")},
 }
 
-// NewStringTrie returns a new StringTrie.
-func NewStringTrie(out io.Writer) *StringTrie {
-	return &StringTrie{
-		Trie: *trie.NewTrie(),
-		out:  out,
-	}
+// PrintFiles walks fsys and prints the path of every regular file it
+// contains, prefixed with "/" to match the original filesystem layout.
+func PrintFiles(out io.Writer, fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			fmt.Fprintln(out, "/"+p)
+		}
+		return nil
+	})
 }
 
-// PrintFiles prints all the files in the trie.
-func (t *StringTrie) PrintFiles() error {
-	err := t.Traverse(t.printFile)
+func main() {
+	t, err := triefs.BuildFromFS(source, ".")
 	if err != nil {
-		return err
+		panic(err)
 	}
-	return nil
-}
-
-// printFile prints the file path if the node is a leaf and its data is "file".
-// it implements trie.TFunc.
-func (t *StringTrie) printFile(step int, path string, data []byte, isLeaf bool) (bool, error) {
-	if bytes.Equal(data, []byte(file)) && isLeaf {
-		fmt.Fprintln(t.out, path)
+	if err := PrintFiles(os.Stdout, triefs.AsFS(t)); err != nil {
+		panic(err)
 	}
-	return true, nil
-}
-
-func main() {
-	t := NewStringTrie(os.Stdout)
-	t.InsertItem("/etc/nginx/nginx.conf", []byte(file))
-	t.InsertItem("/etc/nginx/nginx.conf.d", []byte(dir))
-	t.InsertItem("/etc/sshd", []byte(dir))
-	t.InsertItem("/etc/sshd/sshd.conf", []byte(file))
-	t.InsertItem("/var/log", []byte(dir))
-	t.InsertItem("/var/log/nginx", []byte(dir))
-	t.InsertItem("/var/log/nginx/access.log", []byte(file))
-	t.PrintFiles()
 }
@@ -0,0 +1,161 @@
+package trie
+
+import "strings"
+
+// Iterator is a pull-style cursor over a Trie's keys, visited in sorted
+// order regardless of the Trie's own Sorted setting. Unlike Traverse, it can
+// be paused, resumed, range-scanned with Seek/SeekPrefix, or abandoned
+// without visiting the whole trie.
+//
+// A zero Iterator is not usable; create one with Trie.NewIterator.
+type Iterator struct {
+	t      *Trie
+	stack  []iterFrame
+	prefix []byte
+
+	key  string
+	data []byte
+	err  error
+}
+
+// iterFrame is one node pending visitation: its own leaf value (if emitted
+// is false and it is a leaf) followed by its edges in sorted order starting
+// at idx.
+type iterFrame struct {
+	node     *node
+	path     string
+	edgeKeys []byte
+	idx      int
+	emitted  bool
+}
+
+// NewIterator returns an Iterator positioned before the first key in t.
+func (t *Trie) NewIterator() *Iterator {
+	it := &Iterator{t: t}
+	it.pushFrame(t.root, "")
+	return it
+}
+
+func (it *Iterator) pushFrame(n *node, path string) {
+	it.stack = append(it.stack, iterFrame{
+		node:     n,
+		path:     path,
+		edgeKeys: keys(n.edges, true),
+	})
+}
+
+// Next advances the iterator and reports whether a key is available. On
+// false, check Err to distinguish exhaustion from a backend load failure.
+func (it *Iterator) Next() bool {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+
+		if !top.emitted {
+			top.emitted = true
+			if top.node.isLeaf {
+				if it.prefix != nil && !strings.HasPrefix(top.path, string(it.prefix)) {
+					it.stack = it.stack[:0]
+					return false
+				}
+				it.key = top.path
+				it.data = top.node.data
+				return true
+			}
+		}
+
+		if top.idx >= len(top.edgeKeys) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+
+		k := top.edgeKeys[top.idx]
+		top.idx++
+		e := top.node.edges[k]
+		child := it.t.child(e)
+		if child == nil {
+			it.err = it.t.Err()
+			it.stack = it.stack[:0]
+			return false
+		}
+		it.pushFrame(child, top.path+string(e.label))
+	}
+	return false
+}
+
+// Key returns the key at the iterator's current position.
+func (it *Iterator) Key() string {
+	return it.key
+}
+
+// Value returns the data at the iterator's current position.
+func (it *Iterator) Value() []byte {
+	return it.data
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Seek positions the iterator so the next call to Next returns the smallest
+// key greater than or equal to key, in sorted order.
+func (it *Iterator) Seek(key string) {
+	it.stack = it.stack[:0]
+	it.prefix = nil
+	it.err = nil
+	it.seek(it.t.root, "", []byte(key))
+}
+
+// SeekPrefix positions the iterator at the smallest key under prefix and
+// restricts subsequent Next calls to keys under prefix.
+func (it *Iterator) SeekPrefix(prefix string) {
+	it.Seek(prefix)
+	it.prefix = []byte(prefix)
+}
+
+// seek walks down from n looking for the point where it can resume a sorted
+// traversal that only visits keys >= the original Seek target; path is the
+// key reached so far and target is the remaining suffix to match against.
+func (it *Iterator) seek(n *node, path string, target []byte) {
+	ks := keys(n.edges, true)
+
+	if len(target) == 0 {
+		// every key under n, including n itself if it is a leaf, is >= target.
+		it.stack = append(it.stack, iterFrame{node: n, path: path, edgeKeys: ks})
+		return
+	}
+
+	idx := 0
+	for idx < len(ks) && ks[idx] < target[0] {
+		idx++
+	}
+	// n's own key (== path) is a strict prefix of target here, so it is < target
+	// and must not be emitted; edges before idx sort below target and are skipped.
+	it.stack = append(it.stack, iterFrame{node: n, path: path, edgeKeys: ks, idx: idx, emitted: true})
+
+	if idx == len(ks) || ks[idx] != target[0] {
+		return
+	}
+	// the edge at idx is consumed by this call; Next() resumes at idx+1.
+	it.stack[len(it.stack)-1].idx++
+
+	e := n.edges[ks[idx]]
+	child := it.t.child(e)
+	if child == nil {
+		it.err = it.t.Err()
+		return
+	}
+
+	splitIdx := getFirstMismatch(target, e.label)
+	switch {
+	case splitIdx == len(e.label):
+		// label fully consumed; keep matching against what's left of target.
+		it.seek(child, path+string(e.label), target[splitIdx:])
+	case splitIdx == len(target) || e.label[splitIdx] > target[splitIdx]:
+		// target is a prefix of, or sorts below, the label at the mismatch:
+		// every key under this edge is >= target.
+		it.pushFrame(child, path+string(e.label))
+	}
+	// else the label sorts below target at the mismatch point, so the whole
+	// subtree is < target and is correctly left off the stack.
+}
@@ -0,0 +1,76 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect(it *Iterator) []string {
+	var got []string
+	for it.Next() {
+		got = append(got, it.Key())
+	}
+	return got
+}
+
+func Test_Iterator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		checkFor func(*Trie) bool
+	}{
+		{
+			name:  "iterates all keys in sorted order",
+			input: []string{"world", "hello", "home", "homework", "work"},
+			checkFor: func(trie *Trie) bool {
+				got := collect(trie.NewIterator())
+				want := []string{"hello", "home", "homework", "work", "world"}
+				return assert.ObjectsAreEqual(want, got)
+			},
+		},
+		{
+			name:  "Seek positions at the smallest key >= target",
+			input: []string{"hello", "home", "homework", "world"},
+			checkFor: func(trie *Trie) bool {
+				it := trie.NewIterator()
+				it.Seek("hom")
+				got := collect(it)
+				want := []string{"home", "homework", "world"}
+				return assert.ObjectsAreEqual(want, got)
+			},
+		},
+		{
+			name:  "Seek onto an existing key includes it",
+			input: []string{"hello", "home", "homework", "world"},
+			checkFor: func(trie *Trie) bool {
+				it := trie.NewIterator()
+				it.Seek("home")
+				got := collect(it)
+				want := []string{"home", "homework", "world"}
+				return assert.ObjectsAreEqual(want, got)
+			},
+		},
+		{
+			name:  "SeekPrefix restricts iteration to a prefix",
+			input: []string{"hello", "home", "homework", "work"},
+			checkFor: func(trie *Trie) bool {
+				it := trie.NewIterator()
+				it.SeekPrefix("hom")
+				got := collect(it)
+				want := []string{"home", "homework"}
+				return assert.ObjectsAreEqual(want, got)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trie := NewTrie()
+			for _, word := range tc.input {
+				trie.InsertItem(word, []byte(word+"-value"))
+			}
+			assert.True(t, tc.checkFor(trie))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package trie
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+)
+
+// MerkleTrie wraps a Trie and maintains a cryptographic hash at every node,
+// so that the whole trie can be summarized by a single RootHash and
+// individual keys can be proven to be part of it with a compact Proof. Nodes
+// are always hashed with SHA-256: Proof/VerifyProof have no notion of a
+// pluggable hash, so a MerkleTrie can't offer one either without proofs
+// silently failing to verify.
+//
+// Node hashes are recomputed lazily: InsertItem and DeleteItem only mark the
+// affected nodes dirty, and RootHash/Prove recompute the stale hashes
+// bottom-up the next time they are needed.
+type MerkleTrie struct {
+	Trie
+	hashes map[*node][]byte
+	dirty  map[*node]bool
+}
+
+// NewMerkleTrie returns a new MerkleTrie that hashes nodes with SHA-256.
+func NewMerkleTrie() *MerkleTrie {
+	return &MerkleTrie{
+		Trie:   *NewTrie(),
+		hashes: make(map[*node][]byte),
+		dirty:  make(map[*node]bool),
+	}
+}
+
+// InsertItem inserts the given item into the trie and marks every node on
+// its path dirty.
+func (mt *MerkleTrie) InsertItem(key string, data []byte) {
+	mt.Trie.InsertItem(key, data)
+	mt.markDirty(key)
+}
+
+// DeleteItem deletes the item with the given key. Deletion can merge or
+// drop edges anywhere along the path to the root, so the whole trie is
+// marked dirty rather than trying to track the exact set of touched nodes;
+// nodes dropped by the deletion are also pruned from hashes/dirty so they
+// don't pin memory forever.
+func (mt *MerkleTrie) DeleteItem(key string) {
+	mt.Trie.DeleteItem(key)
+	mt.pruneAndMarkDirty()
+}
+
+// markDirty marks every node on the path to key as needing a hash recompute.
+func (mt *MerkleTrie) markDirty(key string) {
+	cur := mt.root
+	mt.dirty[cur] = true
+	bPref := []byte(key)
+	for len(bPref) > 0 {
+		e, exists := cur.edges[bPref[0]]
+		if !exists {
+			return
+		}
+		splitIdx := getFirstMismatch(bPref, e.label)
+		if splitIdx > len(bPref) {
+			splitIdx = len(bPref)
+		}
+		cur = e.next
+		mt.dirty[cur] = true
+		bPref = bPref[splitIdx:]
+	}
+}
+
+// pruneAndMarkDirty marks every currently reachable node as needing a hash
+// recompute, and drops cached hashes/dirty flags for nodes that deletion's
+// edge merging has made unreachable, so they can be garbage collected.
+func (mt *MerkleTrie) pruneAndMarkDirty() {
+	reachable := make(map[*node]bool)
+	var walk func(n *node)
+	walk = func(n *node) {
+		if reachable[n] {
+			return
+		}
+		reachable[n] = true
+		mt.dirty[n] = true
+		for _, e := range n.edges {
+			walk(e.next)
+		}
+	}
+	walk(mt.root)
+
+	for n := range mt.hashes {
+		if !reachable[n] {
+			delete(mt.hashes, n)
+		}
+	}
+	for n := range mt.dirty {
+		if !reachable[n] {
+			delete(mt.dirty, n)
+		}
+	}
+}
+
+// hashNode returns the hash for n, recomputing it bottom-up if n or any of
+// its descendants is dirty.
+func (mt *MerkleTrie) hashNode(n *node) []byte {
+	if h, ok := mt.hashes[n]; ok && !mt.dirty[n] {
+		return h
+	}
+
+	h := sha256.New()
+	for _, k := range keys(n.edges, true) {
+		e := n.edges[k]
+		h.Write(e.label)
+		h.Write(mt.hashNode(e.next))
+	}
+	h.Write(isLeafByte(n.isLeaf))
+	h.Write(n.data)
+
+	sum := h.Sum(nil)
+	mt.hashes[n] = sum
+	delete(mt.dirty, n)
+	return sum
+}
+
+// RootHash returns the hash summarizing the whole trie.
+func (mt *MerkleTrie) RootHash() []byte {
+	return mt.hashNode(mt.root)
+}
+
+// SiblingHash is the label and hash of one edge out of a node visited while
+// building a Proof.
+type SiblingHash struct {
+	Label []byte
+	Hash  []byte
+}
+
+// ProofStep describes one node visited while walking from the root toward a
+// key, in root-to-leaf order. Siblings holds the label and hash of every
+// edge out of the node other than the one leading to the next step (for the
+// final step, the target node itself, it holds every edge out of it).
+type ProofStep struct {
+	IsLeaf    bool
+	Data      []byte
+	NextLabel []byte
+	Siblings  []SiblingHash
+}
+
+// Proof is a compact inclusion proof for a single key: the information
+// needed to recompute, from the leaf up, every node hash on the path to the
+// root without holding the rest of the trie.
+type Proof []ProofStep
+
+// Prove returns a Proof that key is in the trie with its current data, and
+// true. If key is absent, it returns false; no absence proof is emitted.
+func (mt *MerkleTrie) Prove(key string) (Proof, bool) {
+	var proof Proof
+	cur := mt.root
+	bPref := []byte(key)
+	for len(bPref) > 0 {
+		k := bPref[0]
+		e, exists := cur.edges[k]
+		if !exists {
+			return nil, false
+		}
+		splitIdx := getFirstMismatch(bPref, e.label)
+		if splitIdx != len(e.label) {
+			return nil, false
+		}
+
+		step := ProofStep{IsLeaf: cur.isLeaf, Data: cur.data, NextLabel: e.label}
+		for _, sk := range keys(cur.edges, true) {
+			if sk == k {
+				continue
+			}
+			se := cur.edges[sk]
+			step.Siblings = append(step.Siblings, SiblingHash{Label: se.label, Hash: mt.hashNode(se.next)})
+		}
+		proof = append(proof, step)
+
+		cur = e.next
+		bPref = bPref[splitIdx:]
+	}
+
+	if !cur.isLeaf {
+		return nil, false
+	}
+
+	final := ProofStep{IsLeaf: cur.isLeaf, Data: cur.data}
+	for _, sk := range keys(cur.edges, true) {
+		se := cur.edges[sk]
+		final.Siblings = append(final.Siblings, SiblingHash{Label: se.label, Hash: mt.hashNode(se.next)})
+	}
+	proof = append(proof, final)
+
+	return proof, true
+}
+
+// VerifyProof recomputes node hashes up the path described by proof and
+// reports whether the result equals root. It assumes nodes were hashed with
+// the default SHA-256 MerkleTrie.
+func VerifyProof(root []byte, key string, data []byte, proof Proof) bool {
+	if len(proof) == 0 {
+		return false
+	}
+
+	last := proof[len(proof)-1]
+	if !last.IsLeaf || !bytes.Equal(last.Data, data) {
+		return false
+	}
+
+	h := hashStep(last, nil)
+	for i := len(proof) - 2; i >= 0; i-- {
+		h = hashStep(proof[i], h)
+	}
+	return bytes.Equal(h, root)
+}
+
+// hashStep recomputes the hash of the node described by step. childHash is
+// the already-recomputed hash of the node reached via step.NextLabel, or nil
+// for the final step, which has no continuing edge.
+func hashStep(step ProofStep, childHash []byte) []byte {
+	type labeled struct {
+		label []byte
+		hash  []byte
+	}
+
+	all := make([]labeled, 0, len(step.Siblings)+1)
+	for _, s := range step.Siblings {
+		all = append(all, labeled{s.Label, s.Hash})
+	}
+	if childHash != nil {
+		all = append(all, labeled{step.NextLabel, childHash})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].label[0] < all[j].label[0] })
+
+	h := sha256.New()
+	for _, lh := range all {
+		h.Write(lh.label)
+		h.Write(lh.hash)
+	}
+	h.Write(isLeafByte(step.IsLeaf))
+	h.Write(step.Data)
+	return h.Sum(nil)
+}
+
+// isLeafByte encodes isLeaf as a single byte so it can be folded into a hash.
+func isLeafByte(isLeaf bool) []byte {
+	if isLeaf {
+		return []byte{1}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,124 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MerkleTrie(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		checkFor func(*MerkleTrie) bool
+	}{
+		{
+			name:  "root hash is stable across reads",
+			input: []string{"hello", "world", "home", "work", "homework"},
+			checkFor: func(t *MerkleTrie) bool {
+				first := t.RootHash()
+				second := t.RootHash()
+				return len(first) > 0 && string(first) == string(second)
+			},
+		},
+		{
+			name:  "root hash changes after an insert",
+			input: []string{"hello", "world"},
+			checkFor: func(t *MerkleTrie) bool {
+				before := t.RootHash()
+				t.InsertItem("home", []byte("home-value"))
+				after := t.RootHash()
+				return string(before) != string(after)
+			},
+		},
+		{
+			name:  "root hash changes after a delete",
+			input: []string{"hello", "world", "home", "homework"},
+			checkFor: func(t *MerkleTrie) bool {
+				before := t.RootHash()
+				t.DeleteItem("home")
+				after := t.RootHash()
+				return string(before) != string(after)
+			},
+		},
+		{
+			name:  "a proof for an absent key fails",
+			input: []string{"hello", "world"},
+			checkFor: func(t *MerkleTrie) bool {
+				_, ok := t.Prove("nope")
+				return !ok
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trie := NewMerkleTrie()
+			for _, word := range tc.input {
+				trie.InsertItem(word, []byte(word+"-value"))
+			}
+			assert.True(t, tc.checkFor(trie))
+		})
+	}
+}
+
+func Test_ProveAndVerifyProof(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []string
+		key   string
+		data  []byte
+		valid bool
+	}{
+		{
+			name:  "single word",
+			input: []string{"hello"},
+			key:   "hello",
+			data:  []byte("hello-value"),
+			valid: true,
+		},
+		{
+			name:  "word sharing a prefix with a sibling",
+			input: []string{"home", "homework", "hello"},
+			key:   "homework",
+			data:  []byte("homework-value"),
+			valid: true,
+		},
+		{
+			name:  "wrong data fails verification",
+			input: []string{"hello", "world"},
+			key:   "hello",
+			data:  []byte("wrong-value"),
+			valid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trie := NewMerkleTrie()
+			for _, word := range tc.input {
+				trie.InsertItem(word, []byte(word+"-value"))
+			}
+
+			root := trie.RootHash()
+			proof, ok := trie.Prove(tc.key)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.valid, VerifyProof(root, tc.key, tc.data, proof))
+		})
+	}
+}
+
+func Test_MerkleTrie_DeleteDoesNotLeakNodes(t *testing.T) {
+	trie := NewMerkleTrie()
+	for i := 0; i < 2000; i++ {
+		trie.InsertItem("key", []byte("value"))
+		trie.RootHash()
+		trie.DeleteItem("key")
+	}
+	trie.InsertItem("key", []byte("value"))
+	trie.RootHash()
+
+	assert.LessOrEqual(t, len(trie.hashes), 2)
+	assert.LessOrEqual(t, len(trie.dirty), 2)
+}
@@ -0,0 +1,90 @@
+package trie
+
+// LongestPrefix returns the longest key in the trie that is a prefix of key,
+// along with its data. Unlike getNode, it does not require an exact match:
+// it keeps track of the deepest leaf seen while walking down and returns it
+// once the walk falls off the tree or stops partway through an edge label.
+// ok is false if no ancestor of key is a leaf.
+func (t *Trie) LongestPrefix(key string) (matchedKey string, data []byte, ok bool) {
+	cur := t.root
+	bPref := []byte(key)
+	path := []byte{}
+
+	if cur.isLeaf {
+		matchedKey, data, ok = string(path), cur.data, true
+	}
+
+	for len(bPref) > 0 {
+		e, exists := cur.edges[bPref[0]]
+		if !exists {
+			break
+		}
+		splitIdx := getFirstMismatch(bPref, e.label)
+		if splitIdx != len(e.label) {
+			// key falls off partway through this edge's label.
+			break
+		}
+		child := t.child(e)
+		if child == nil {
+			break
+		}
+
+		path = append(path, e.label...)
+		cur = child
+		bPref = bPref[splitIdx:]
+
+		if cur.isLeaf {
+			matchedKey, data, ok = string(path), cur.data, true
+		}
+	}
+
+	return matchedKey, data, ok
+}
+
+// WalkPath invokes fn at every leaf on the path from the root toward key,
+// shallowest first, stopping early if fn returns false or an error. This is
+// the routing counterpart to Traverse: useful for hierarchical configuration
+// lookup, ACL resolution, or CIDR/URL-style routing, where every ancestor of
+// a key may carry data worth visiting on the way down.
+func (t *Trie) WalkPath(key string, fn TFunc) error {
+	cur := t.root
+	bPref := []byte(key)
+	path := []byte{}
+	step := 0
+
+	if cur.isLeaf {
+		next, err := fn(step, string(path), cur.data, true)
+		if err != nil || !next {
+			return err
+		}
+	}
+
+	for len(bPref) > 0 {
+		e, exists := cur.edges[bPref[0]]
+		if !exists {
+			return nil
+		}
+		splitIdx := getFirstMismatch(bPref, e.label)
+		if splitIdx != len(e.label) {
+			return nil
+		}
+		child := t.child(e)
+		if child == nil {
+			return nil
+		}
+
+		path = append(path, e.label...)
+		cur = child
+		bPref = bPref[splitIdx:]
+		step++
+
+		if cur.isLeaf {
+			next, err := fn(step, string(path), cur.data, true)
+			if err != nil || !next {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LongestPrefix(t *testing.T) {
+	trie := NewTrie()
+	for _, word := range []string{"/etc", "/etc/nginx", "/etc/nginx/nginx.conf"} {
+		trie.InsertItem(word, []byte(word+"-value"))
+	}
+
+	testCases := []struct {
+		name      string
+		key       string
+		wantKey   string
+		wantValue string
+		wantOK    bool
+	}{
+		{
+			name:      "exact match",
+			key:       "/etc/nginx",
+			wantKey:   "/etc/nginx",
+			wantValue: "/etc/nginx-value",
+			wantOK:    true,
+		},
+		{
+			name:      "key continues past every leaf",
+			key:       "/etc/nginx/nginx.conf.d",
+			wantKey:   "/etc/nginx/nginx.conf",
+			wantValue: "/etc/nginx/nginx.conf-value",
+			wantOK:    true,
+		},
+		{
+			name:      "key lands mid-edge, returns the last complete leaf ancestor",
+			key:       "/etc/nginx/sites-enabled",
+			wantKey:   "/etc/nginx",
+			wantValue: "/etc/nginx-value",
+			wantOK:    true,
+		},
+		{
+			name:   "no ancestor is a leaf",
+			key:    "/var/log",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, data, ok := trie.LongestPrefix(tc.key)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.wantKey, key)
+				assert.Equal(t, tc.wantValue, string(data))
+			}
+		})
+	}
+}
+
+func Test_WalkPath(t *testing.T) {
+	trie := NewTrie()
+	for _, word := range []string{"/etc", "/etc/nginx", "/etc/nginx/nginx.conf"} {
+		trie.InsertItem(word, []byte(word+"-value"))
+	}
+
+	var visited []string
+	err := trie.WalkPath("/etc/nginx/nginx.conf", func(step int, path string, data []byte, isLeaf bool) (bool, error) {
+		visited = append(visited, path)
+		return true, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/etc", "/etc/nginx", "/etc/nginx/nginx.conf"}, visited)
+}
+
+func Test_WalkPath_StopsEarly(t *testing.T) {
+	trie := NewTrie()
+	for _, word := range []string{"/etc", "/etc/nginx", "/etc/nginx/nginx.conf"} {
+		trie.InsertItem(word, []byte(word+"-value"))
+	}
+
+	var visited []string
+	err := trie.WalkPath("/etc/nginx/nginx.conf", func(step int, path string, data []byte, isLeaf bool) (bool, error) {
+		visited = append(visited, path)
+		return path != "/etc/nginx", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/etc", "/etc/nginx"}, visited)
+}
@@ -0,0 +1,179 @@
+package trie
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SyncTrie is a concurrency-safe wrapper around Trie. InsertItem and
+// DeleteItem are serialized with a mutex and use copy-on-write: only the
+// nodes on the mutation's path are cloned before the new root is swapped in
+// atomically, so a Snapshot taken before a mutation keeps seeing a
+// consistent, unmodified view of the trie even while the mutation runs.
+type SyncTrie struct {
+	mu     sync.Mutex
+	root   atomic.Pointer[node]
+	sorted atomic.Bool
+}
+
+// NewSyncTrie returns a new, empty SyncTrie.
+func NewSyncTrie() *SyncTrie {
+	st := &SyncTrie{}
+	st.root.Store(&node{edges: make(map[byte]*edge)})
+	return st
+}
+
+// Sorted sets the sorted flag used by Snapshots taken from this SyncTrie.
+func (st *SyncTrie) Sorted(sorted bool) {
+	st.sorted.Store(sorted)
+}
+
+// Snapshot returns a read-only Trie over the current root. Because
+// InsertItem and DeleteItem never mutate a node already reachable from a
+// published root, the returned Trie can be used with Traverse, NewIterator
+// or Keys without locking, even while mutations run concurrently.
+func (st *SyncTrie) Snapshot() *Trie {
+	return &Trie{root: st.root.Load(), sorted: st.sorted.Load()}
+}
+
+// HasItem returns true if the given key exists in the trie.
+func (st *SyncTrie) HasItem(key string) bool {
+	return st.Snapshot().HasItem(key)
+}
+
+// GetItem returns the data for the given item.
+func (st *SyncTrie) GetItem(key string) []byte {
+	return st.Snapshot().GetItem(key)
+}
+
+// InsertItem inserts the given item into the trie.
+func (st *SyncTrie) InsertItem(key string, data []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	newRoot := cloneNode(st.root.Load())
+	cur := newRoot
+	bPref := []byte(key)
+	for len(bPref) > 0 {
+		k := bPref[0]
+		if cur.edges == nil {
+			cur.edges = make(map[byte]*edge)
+		}
+		currEdge, exists := cur.edges[k]
+		if !exists {
+			cur.edges[k] = &edge{
+				label: bPref,
+				next: &node{
+					isLeaf: true,
+					data:   data,
+				},
+			}
+			break
+		}
+
+		newEdge := cloneEdge(currEdge)
+		newChild := cloneNode(currEdge.next)
+		newEdge.next = newChild
+		cur.edges[k] = newEdge
+
+		curStr, curStrLen := bPref, len(bPref)
+		labelLen := len(newEdge.label)
+		if curStrLen > labelLen {
+			curStr = bPref[:labelLen]
+		}
+
+		splitIdx := getFirstMismatch(curStr, newEdge.label)
+		if splitIdx != labelLen {
+			tail := newEdge.label[splitIdx:]
+			newEdge.label = newEdge.label[:splitIdx]
+			split := &node{edges: make(map[byte]*edge)}
+			split.edges[tail[0]] = &edge{label: tail, next: newChild}
+			newEdge.next = split
+			newChild = split
+		}
+
+		if len(bPref) == len(newEdge.label) {
+			newChild.isLeaf = true
+			newChild.data = data
+		}
+
+		cur = newChild
+		bPref = bPref[splitIdx:]
+	}
+
+	st.root.Store(newRoot)
+}
+
+// DeleteItem deletes the item with the given key.
+func (st *SyncTrie) DeleteItem(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	root := st.root.Load()
+	newRoot := cowDelete(root, root, []byte(key))
+	if newRoot == nil {
+		newRoot = &node{edges: make(map[byte]*edge)}
+	}
+	st.root.Store(newRoot)
+}
+
+// cloneNode returns a shallow copy of n: its own edges map is copied, but
+// the edges (and everything below them) are shared with n until something
+// along that path is next mutated.
+func cloneNode(n *node) *node {
+	c := &node{isLeaf: n.isLeaf, data: n.data}
+	if n.edges != nil {
+		c.edges = make(map[byte]*edge, len(n.edges))
+		for k, e := range n.edges {
+			c.edges[k] = e
+		}
+	}
+	return c
+}
+
+// cloneEdge returns a shallow copy of e.
+func cloneEdge(e *edge) *edge {
+	return &edge{label: e.label, next: e.next, ref: e.ref}
+}
+
+// cowDelete mirrors Trie.delete, but clones every node it touches instead of
+// mutating it in place, so a root published before the call started remains
+// valid for concurrent readers.
+func cowDelete(root, n *node, key []byte) *node {
+	if len(key) == 0 {
+		if n.edges == nil && n != root {
+			return nil
+		}
+		c := cloneNode(n)
+		c.isLeaf = false
+		return c
+	}
+
+	currEdge, exists := n.edges[key[0]]
+	if !exists {
+		return n
+	}
+
+	deleted := cowDelete(root, currEdge.next, key[len(currEdge.label):])
+
+	c := cloneNode(n)
+	switch {
+	case deleted == nil:
+		delete(c.edges, key[0])
+		if len(c.edges) == 0 && !c.isLeaf && n != root {
+			return nil
+		}
+	case len(deleted.edges) == 1 && !deleted.isLeaf:
+		delete(c.edges, key[0])
+		for _, v := range deleted.edges {
+			c.edges[key[0]] = &edge{
+				label: append(append([]byte{}, currEdge.label...), v.label...),
+				next:  v.next,
+			}
+		}
+	default:
+		c.edges[key[0]] = &edge{label: currEdge.label, next: deleted}
+	}
+
+	return c
+}
@@ -0,0 +1,99 @@
+package trie
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SyncTrie(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		checkFor func(*SyncTrie) bool
+	}{
+		{
+			name:  "test for a single word",
+			input: []string{"hello"},
+			checkFor: func(t *SyncTrie) bool {
+				return t.HasItem("hello")
+			},
+		},
+		{
+			name:  "test for deleting a word",
+			input: []string{"home", "homework"},
+			checkFor: func(t *SyncTrie) bool {
+				t.DeleteItem("home")
+				return t.HasItem("homework") && !t.HasItem("home")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := NewSyncTrie()
+			for _, word := range tc.input {
+				st.InsertItem(word, []byte(word+"-value"))
+			}
+			assert.True(t, tc.checkFor(st))
+		})
+	}
+}
+
+func Test_SyncTrie_SnapshotIsolation(t *testing.T) {
+	st := NewSyncTrie()
+	st.InsertItem("home", []byte("home-value"))
+
+	snap := st.Snapshot()
+
+	st.InsertItem("homework", []byte("homework-value"))
+	st.DeleteItem("home")
+
+	assert.True(t, snap.HasItem("home"))
+	assert.Equal(t, "home-value", string(snap.GetItem("home")))
+	assert.False(t, snap.HasItem("homework"))
+
+	assert.False(t, st.HasItem("home"))
+	assert.True(t, st.HasItem("homework"))
+}
+
+func Test_SyncTrie_ConcurrentMutation(t *testing.T) {
+	st := NewSyncTrie()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a'+i%26)) + "-key"
+			st.InsertItem(key, []byte("value"))
+		}(i)
+	}
+	wg.Wait()
+
+	snap := st.Snapshot()
+	assert.True(t, snap.HasItem("a-key"))
+}
+
+func Test_SyncTrie_ConcurrentSortedAndSnapshot(t *testing.T) {
+	st := NewSyncTrie()
+	st.InsertItem("hello", []byte("hello-value"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			st.Sorted(i%2 == 0)
+		}(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = st.Snapshot()
+			_ = st.HasItem("hello")
+			_ = st.GetItem("hello")
+		}()
+	}
+	wg.Wait()
+}
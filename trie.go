@@ -1,5 +1,5 @@
 //This package implements a trie data structure as proposed in https://en.wikipedia.org/wiki/Radix_tree.
-//The trie is not thread-safe.
+//The trie is not thread-safe. For concurrent use, see SyncTrie.
 
 package trie
 
@@ -14,6 +14,13 @@ type TFunc func(step int, path string, data []byte, isLeaf bool) (bool, error)
 type Trie struct {
 	root   *node
 	sorted bool
+
+	// backend, cache and dirty are only set by NewTrieWithBackend/OpenTrie;
+	// see backend.go.
+	backend Backend
+	cache   *lru
+	dirty   map[*node]bool
+	err     error
 }
 
 type node struct {
@@ -24,7 +31,12 @@ type node struct {
 
 type edge struct {
 	label []byte
-	next  *node
+	// next is the in-memory child, or nil if only ref is known and the
+	// child has not been loaded from the backend yet.
+	next *node
+	// ref is the content address of the child in the backend, set on
+	// edges of nodes decoded from the backend (by OpenTrie or on lazy load).
+	ref []byte
 }
 
 // NewTrie returns a new Trie.
@@ -64,6 +76,11 @@ func (t *Trie) InsertItem(key string, data []byte) {
 			}
 			break
 		}
+		if currEdge.next == nil {
+			// edge was loaded from a backend; materialize it before mutating.
+			currEdge.next = t.child(currEdge)
+			currEdge.ref = nil
+		}
 		curStr, curStrLen := bPref, len(bPref)
 		labelLen := len(currEdge.label)
 		// if the current string is longer than the edge label
@@ -92,6 +109,9 @@ func (t *Trie) InsertItem(key string, data []byte) {
 		cur = currEdge.next
 		bPref = bPref[splitIdx:]
 	}
+	if t.backend != nil {
+		t.markBackendDirty(key)
+	}
 }
 
 // getFirstMismatch returns the index of the first mismatch between current and
@@ -122,7 +142,7 @@ func (t *Trie) getNode(prefix string) *node {
 		if splitIdx != len(edge.label) {
 			return nil
 		}
-		cur = edge.next
+		cur = t.child(edge)
 		bPref = bPref[splitIdx:]
 	}
 	return cur
@@ -137,6 +157,9 @@ func (t *Trie) HasItem(key string) bool {
 // DeleteItem deletes the item with the given key.
 func (t *Trie) DeleteItem(key string) {
 	t.root = t.delete(t.root, []byte(key))
+	if t.backend != nil {
+		t.markBackendDirtyAll()
+	}
 }
 
 func (t *Trie) delete(node *node, key []byte) *node {
@@ -152,6 +175,11 @@ func (t *Trie) delete(node *node, key []byte) *node {
 	if !exists {
 		return node
 	}
+	if currEdge.next == nil {
+		// edge was loaded from a backend; materialize it before mutating.
+		currEdge.next = t.child(currEdge)
+		currEdge.ref = nil
+	}
 
 	deleted := t.delete(currEdge.next, key[len(currEdge.label):])
 	if deleted == nil {
@@ -210,11 +238,11 @@ func (t *Trie) startsWith(prefix string) (*node, string) {
 		splitIdx := getFirstMismatch(bPref, edge.label)
 		if splitIdx != len(edge.label) {
 			if splitIdx == len(bPref) {
-				return edge.next, prefix + string(edge.label[splitIdx:])
+				return t.child(edge), prefix + string(edge.label[splitIdx:])
 			}
 			return nil, ""
 		}
-		cur = edge.next
+		cur = t.child(edge)
 		bPref = bPref[splitIdx:]
 	}
 	return cur, prefix
@@ -243,7 +271,7 @@ func (t *Trie) traverse(node *node, step int, path []byte, fn TFunc) error {
 
 	keys := keys(node.edges, t.sorted)
 	for _, k := range keys {
-		err = t.traverse(node.edges[k].next, step+1, append(path, node.edges[k].label...), fn)
+		err = t.traverse(t.child(node.edges[k]), step+1, append(path, node.edges[k].label...), fn)
 		if err != nil {
 			return err
 		}
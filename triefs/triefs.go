@@ -0,0 +1,264 @@
+// Package triefs adapts trie.Trie to the standard io/fs package: BuildFromFS
+// turns any fs.FS into a trie keyed by path, and AsFS turns a trie back into
+// a read-only fs.FS, fs.ReadDirFS and fs.StatFS.
+package triefs
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	trie "github.com/souleb/gotrie"
+)
+
+// BuildFromFS walks fsys starting at root and inserts every path below root
+// (root itself is not inserted) into a new trie.Trie, storing the type bits
+// of each entry's fs.FileMode (d.Type(); permission bits are not kept) and,
+// for regular files, its full content as its data, so AsFS can later hand
+// back the original bytes.
+func BuildFromFS(fsys fs.FS, root string) (*trie.Trie, error) {
+	t := trie.NewTrie()
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		var content []byte
+		if !d.IsDir() {
+			content, err = fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+		}
+		t.InsertItem(p, encodeEntry(d.Type(), content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// BuildFromFSFunc is like BuildFromFS, but lets the caller decide what data
+// to store for each visited path instead of its fs.FileMode. The root path
+// itself is not inserted: AsFS always treats "." as the implicit top of the
+// tree, and inserting it as a literal key would surface it as an invalid
+// "." directory entry.
+func BuildFromFSFunc(fsys fs.FS, root string, dataFn func(path string, d fs.DirEntry) []byte) (*trie.Trie, error) {
+	t := trie.NewTrie()
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		t.InsertItem(p, dataFn(p, d))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// AsFS exposes t, as built by BuildFromFS, as a read-only fs.FS. The
+// returned value also implements fs.ReadDirFS and fs.StatFS.
+func AsFS(t *trie.Trie) fs.FS {
+	return &triefsFS{t: t}
+}
+
+type triefsFS struct {
+	t *trie.Trie
+}
+
+var (
+	_ fs.FS        = (*triefsFS)(nil)
+	_ fs.ReadDirFS = (*triefsFS)(nil)
+	_ fs.StatFS    = (*triefsFS)(nil)
+)
+
+func (f *triefsFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return entryInfo{name: ".", mode: fs.ModeDir}, nil
+	}
+	if !f.t.HasItem(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	mode, content := decodeEntry(f.t.GetItem(name))
+	return entryInfo{name: path.Base(name), mode: mode, size: int64(len(content))}, nil
+}
+
+// ReadDir lists the direct children of name, derived from the full
+// descendant keys Trie.Keys returns under name's prefix.
+func (f *triefsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		if !f.t.HasItem(name) {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+		}
+		mode, _ := decodeEntry(f.t.GetItem(name))
+		if !mode.IsDir() {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+		}
+	}
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	for _, k := range f.t.Keys(prefix) {
+		rest := strings.TrimPrefix(k, prefix)
+		if rest == "" {
+			continue
+		}
+		seg, isDir := rest, false
+		if idx := strings.IndexByte(rest, '/'); idx >= 0 {
+			seg, isDir = rest[:idx], true
+		}
+		if seen[seg] {
+			continue
+		}
+		seen[seg] = true
+
+		mode, content := decodeEntry(f.t.GetItem(prefix + seg))
+		if isDir {
+			mode |= fs.ModeDir
+		}
+		entries = append(entries, entryInfo{name: seg, mode: mode, size: int64(len(content))})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Open implements fs.FS. Directories (including the root) open as
+// fs.ReadDirFile values.
+func (f *triefsFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." && !f.t.HasItem(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var mode fs.FileMode
+	if name != "." {
+		mode, _ = decodeEntry(f.t.GetItem(name))
+	}
+	if name == "." || mode.IsDir() {
+		entries, err := f.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &openDir{name: name, entries: entries}, nil
+	}
+
+	info, err := f.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	_, content := decodeEntry(f.t.GetItem(name))
+	return &openFile{info: info, data: content}, nil
+}
+
+// entryInfo implements both fs.DirEntry and fs.FileInfo for a single trie
+// entry; ReadDir and Stat share it since a trie node carries no size or
+// modification time of its own.
+type entryInfo struct {
+	name string
+	mode fs.FileMode
+	size int64
+}
+
+func (e entryInfo) Name() string               { return e.name }
+func (e entryInfo) IsDir() bool                { return e.mode.IsDir() }
+func (e entryInfo) Type() fs.FileMode          { return e.mode.Type() }
+func (e entryInfo) Info() (fs.FileInfo, error) { return e, nil }
+func (e entryInfo) Size() int64                { return e.size }
+func (e entryInfo) Mode() fs.FileMode          { return e.mode }
+func (e entryInfo) ModTime() time.Time         { return time.Time{} }
+func (e entryInfo) Sys() any                   { return nil }
+
+type openDir struct {
+	name    string
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openDir) Stat() (fs.FileInfo, error) {
+	return entryInfo{name: path.Base(d.name), mode: fs.ModeDir}, nil
+}
+
+func (d *openDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *openDir) Close() error { return nil }
+
+func (d *openDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		rest := d.entries[d.offset:]
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if d.offset >= len(d.entries) {
+		return nil, io.EOF
+	}
+	end := d.offset + n
+	if end > len(d.entries) {
+		end = len(d.entries)
+	}
+	res := d.entries[d.offset:end]
+	d.offset = end
+	return res, nil
+}
+
+type openFile struct {
+	info   fs.FileInfo
+	data   []byte
+	offset int
+}
+
+func (f *openFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *openFile) Read(b []byte) (int, error) {
+	if f.offset >= len(f.data) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[f.offset:])
+	f.offset += n
+	return n, nil
+}
+
+func (f *openFile) Close() error { return nil }
+
+// encodeEntry packs an entry's fs.FileMode and, for regular files, its
+// content into the single blob stored as a trie item's data.
+func encodeEntry(m fs.FileMode, content []byte) []byte {
+	b := make([]byte, 4, 4+len(content))
+	binary.BigEndian.PutUint32(b, uint32(m))
+	return append(b, content...)
+}
+
+// decodeEntry is the inverse of encodeEntry.
+func decodeEntry(b []byte) (fs.FileMode, []byte) {
+	if len(b) < 4 {
+		return 0, nil
+	}
+	return fs.FileMode(binary.BigEndian.Uint32(b)), b[4:]
+}
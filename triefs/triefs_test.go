@@ -0,0 +1,45 @@
+package triefs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildFromFS_AsFS(t *testing.T) {
+	src := fstest.MapFS{
+		"etc/nginx/nginx.conf":     &fstest.MapFile{Data: []byte("server {}")},
+		"etc/sshd/sshd.conf":       &fstest.MapFile{Data: []byte("Port 22")},
+		"var/log/nginx/access.log": &fstest.MapFile{Data: []byte("- - -")},
+	}
+
+	trie, err := BuildFromFS(src, ".")
+	require.NoError(t, err)
+
+	got := AsFS(trie)
+	require.NoError(t, fstest.TestFS(got,
+		"etc/nginx/nginx.conf",
+		"etc/sshd/sshd.conf",
+		"var/log/nginx/access.log",
+	))
+
+	data, err := fs.ReadFile(got, "etc/nginx/nginx.conf")
+	require.NoError(t, err)
+	assert.Equal(t, "server {}", string(data))
+
+	entries, err := fs.ReadDir(got, "etc")
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+		assert.True(t, e.IsDir())
+	}
+	assert.Equal(t, []string{"nginx", "sshd"}, names)
+
+	info, err := fs.Stat(got, "etc/nginx/nginx.conf")
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+}